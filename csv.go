@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+// requiredColumns lists the header columns a mapping CSV file must contain,
+// in any order.
+var requiredColumns = []string{"staff_pass_id", "team_name", "created_at"}
+
+// HeaderError reports that a mapping CSV file's header row is missing a
+// required column.
+type HeaderError struct {
+	FilePath string
+	Column   string
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("%s: missing required column %q", e.FilePath, e.Column)
+}
+
+// RowError describes a single rejected data row, pinpointing the file, line,
+// and column so an operator can jump straight to the bad cell in their
+// spreadsheet.
+type RowError struct {
+	FilePath string
+	Line     int
+	Column   int
+	Reason   string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.FilePath, e.Line, e.Column, e.Reason)
+}
+
+// parseHeader validates header against requiredColumns, matching by column
+// name rather than position, and returns a lookup from column name to its
+// index in each record.
+func parseHeader(filePath string, header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+	for _, column := range requiredColumns {
+		if _, ok := index[column]; !ok {
+			return nil, &HeaderError{FilePath: filePath, Column: column}
+		}
+	}
+	return index, nil
+}
+
+// parseMappingRow converts a single CSV record into a StaffMapping using
+// columnIndex, or reports why the row was rejected (a short row, a
+// non-numeric created_at). reader is used only to locate the bad cell for
+// the returned *RowError.
+func parseMappingRow(filePath string, reader *csv.Reader, columnIndex map[string]int, record []string) (StaffMapping, *RowError) {
+	if len(record) < len(columnIndex) {
+		line, _ := reader.FieldPos(0)
+		return StaffMapping{}, &RowError{
+			FilePath: filePath,
+			Line:     line,
+			Reason:   "row has fewer fields than the header",
+		}
+	}
+
+	createdAtRaw := record[columnIndex["created_at"]]
+	createdAt, err := strconv.ParseInt(createdAtRaw, 10, 64)
+	if err != nil {
+		line, column := reader.FieldPos(columnIndex["created_at"])
+		return StaffMapping{}, &RowError{
+			FilePath: filePath,
+			Line:     line,
+			Column:   column,
+			Reason:   fmt.Sprintf("invalid epoch milliseconds %q: %v", createdAtRaw, err),
+		}
+	}
+
+	return StaffMapping{
+		StaffPassID: record[columnIndex["staff_pass_id"]],
+		TeamName:    record[columnIndex["team_name"]],
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+// openMappingCSV opens filePath and validates its header, returning the
+// *csv.Reader positioned at the first data row along with the column lookup
+// LoadMappingFromCSV and LoadMappingFromCSVStrict both need to parse rows.
+func openMappingCSV(filePath string) (*os.File, *csv.Reader, map[string]int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to open CSV file: %w", err)
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	columnIndex, err := parseHeader(filePath, header)
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, err
+	}
+
+	return file, reader, columnIndex, nil
+}
+
+// LoadMappingFromCSV streams a CSV file at filePath and returns the
+// StaffMapping rows that parsed successfully. The header row must contain
+// "staff_pass_id", "team_name", and "created_at" (epoch milliseconds) in any
+// order, or a *HeaderError is returned. Rows that fail to parse (a short row,
+// a non-numeric created_at) are not silently dropped: each is reported in the
+// returned []RowError, including the line and column of the bad cell. Use
+// LoadMappingFromCSVStrict to fail fast on the first row error instead.
+func LoadMappingFromCSV(filePath string) ([]StaffMapping, []RowError, error) {
+	file, reader, columnIndex, err := openMappingCSV(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var mappings []StaffMapping
+	var rowErrors []RowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{FilePath: filePath, Reason: err.Error()})
+			continue
+		}
+
+		mapping, rowErr := parseMappingRow(filePath, reader, columnIndex, record)
+		if rowErr != nil {
+			rowErrors = append(rowErrors, *rowErr)
+			continue
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rowErrors, nil
+}
+
+// LoadMappingFromCSVStrict behaves like LoadMappingFromCSV but stops reading
+// and returns as soon as the first row is rejected, instead of scanning the
+// rest of the file.
+func LoadMappingFromCSVStrict(filePath string) ([]StaffMapping, error) {
+	file, reader, columnIndex, err := openMappingCSV(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mappings []StaffMapping
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, RowError{FilePath: filePath, Reason: err.Error()}
+		}
+
+		mapping, rowErr := parseMappingRow(filePath, reader, columnIndex, record)
+		if rowErr != nil {
+			return nil, *rowErr
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// logRowErrors prints a warning for each row rejected while loading a
+// mapping file, so operators can see exactly what to fix without the process
+// failing outright.
+func logRowErrors(rowErrors []RowError) {
+	for _, rowErr := range rowErrors {
+		log.Printf("warning: skipping mapping row: %v", rowErr)
+	}
+}
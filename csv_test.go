@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mapping.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("unable to write temp CSV file: %v", err)
+	}
+	return path
+}
+
+// TestLoadMappingFromCSV creates a temporary CSV file, loads the data, and
+// verifies correct parsing.
+func TestLoadMappingFromCSV(t *testing.T) {
+	path := writeTempCSV(t, `staff_pass_id,team_name,created_at
+STAFF_H123804820G,BASS,1623772799000
+MANAGER_T999888420B,RUST,1623772799000
+BOSS_T000000001P,RUST,1623872111000
+`)
+
+	mappings, rowErrors, err := LoadMappingFromCSV(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(rowErrors) != 0 {
+		t.Fatalf("expected no row errors, got %+v", rowErrors)
+	}
+	if len(mappings) != 3 {
+		t.Errorf("expected 3 mappings, got %d", len(mappings))
+	}
+	if mappings[0].StaffPassID != "STAFF_H123804820G" || mappings[0].TeamName != "BASS" {
+		t.Errorf("unexpected mapping record: %+v", mappings[0])
+	}
+}
+
+// TestLoadMappingFromCSVReorderedHeader verifies that columns are matched by
+// name, not position.
+func TestLoadMappingFromCSVReorderedHeader(t *testing.T) {
+	path := writeTempCSV(t, `created_at,staff_pass_id,team_name
+1623772799000,STAFF_H123804820G,BASS
+`)
+
+	mappings, rowErrors, err := LoadMappingFromCSV(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(rowErrors) != 0 {
+		t.Fatalf("expected no row errors, got %+v", rowErrors)
+	}
+	if len(mappings) != 1 || mappings[0].StaffPassID != "STAFF_H123804820G" || mappings[0].CreatedAt != 1623772799000 {
+		t.Errorf("unexpected mapping record: %+v", mappings)
+	}
+}
+
+// TestLoadMappingFromCSVMissingHeader verifies that a missing required
+// column produces a *HeaderError.
+func TestLoadMappingFromCSVMissingHeader(t *testing.T) {
+	path := writeTempCSV(t, `staff_pass_id,team_name
+STAFF_H123804820G,BASS
+`)
+
+	_, _, err := LoadMappingFromCSV(path)
+	var headerErr *HeaderError
+	if !errors.As(err, &headerErr) {
+		t.Fatalf("expected a *HeaderError, got %v (%T)", err, err)
+	}
+	if headerErr.Column != "created_at" {
+		t.Errorf("expected missing column created_at, got %q", headerErr.Column)
+	}
+}
+
+// TestLoadMappingFromCSVRowErrors verifies that a non-numeric created_at and
+// a short row are reported as RowErrors instead of silently skipped, and
+// that valid rows around them still load.
+func TestLoadMappingFromCSVRowErrors(t *testing.T) {
+	path := writeTempCSV(t, `staff_pass_id,team_name,created_at
+STAFF_H123804820G,BASS,1623772799000
+MANAGER_T999888420B,RUST,not-a-number
+BOSS_T000000001P,RUST
+ANOTHER_T1,RUST,1623872111000
+`)
+
+	mappings, rowErrors, err := LoadMappingFromCSV(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(mappings) != 2 {
+		t.Fatalf("expected 2 valid mappings, got %d: %+v", len(mappings), mappings)
+	}
+	if len(rowErrors) != 2 {
+		t.Fatalf("expected 2 row errors, got %d: %+v", len(rowErrors), rowErrors)
+	}
+}
+
+// TestLoadMappingFromCSVStrictFailsFast verifies that the strict variant
+// returns an error as soon as any row is rejected.
+func TestLoadMappingFromCSVStrictFailsFast(t *testing.T) {
+	path := writeTempCSV(t, `staff_pass_id,team_name,created_at
+STAFF_H123804820G,BASS,1623772799000
+MANAGER_T999888420B,RUST,not-a-number
+`)
+
+	if _, err := LoadMappingFromCSVStrict(path); err == nil {
+		t.Fatal("expected an error for the invalid row, got nil")
+	}
+}
+
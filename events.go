@@ -0,0 +1,40 @@
+package main
+
+// RedemptionEventKind identifies what happened to a team's redemption state
+// at a point in time.
+type RedemptionEventKind string
+
+const (
+	// EventRedeemed records that a team successfully redeemed their gift.
+	EventRedeemed RedemptionEventKind = "redeemed"
+	// EventRevoked records that a previously granted redemption was undone.
+	EventRevoked RedemptionEventKind = "revoked"
+)
+
+// RedemptionEvent is one entry in a team's redemption timeline.
+type RedemptionEvent struct {
+	TeamName  string
+	Kind      RedemptionEventKind
+	Timestamp int64
+	Reason    string // set only for EventRevoked
+}
+
+// foldEvents replays a chronological list of RedemptionEvents into the
+// currently active redemptions and the full per-team history in one pass.
+// A team is eligible to redeem again as soon as its most recent event is a
+// revocation rather than a redemption.
+func foldEvents(events []RedemptionEvent) (map[string]Redemption, map[string][]RedemptionEvent) {
+	redemptions := make(map[string]Redemption)
+	history := make(map[string][]RedemptionEvent)
+
+	for _, event := range events {
+		history[event.TeamName] = append(history[event.TeamName], event)
+		switch event.Kind {
+		case EventRedeemed:
+			redemptions[event.TeamName] = Redemption{TeamName: event.TeamName, RedeemedAt: event.Timestamp}
+		case EventRevoked:
+			delete(redemptions, event.TeamName)
+		}
+	}
+	return redemptions, history
+}
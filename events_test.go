@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+// TestFoldEventsAllowsRedeemAfterRevoke verifies that a team's state folds to
+// "not redeemed" once its most recent event is a revocation, while its full
+// history is preserved.
+func TestFoldEventsAllowsRedeemAfterRevoke(t *testing.T) {
+	events := []RedemptionEvent{
+		{TeamName: "BASS", Kind: EventRedeemed, Timestamp: 1000},
+		{TeamName: "BASS", Kind: EventRevoked, Timestamp: 2000, Reason: "spoiled gift"},
+		{TeamName: "RUST", Kind: EventRedeemed, Timestamp: 3000},
+	}
+
+	redemptions, history := foldEvents(events)
+
+	if _, redeemed := redemptions["BASS"]; redeemed {
+		t.Error("expected BASS to not be currently redeemed after revoke")
+	}
+	if _, redeemed := redemptions["RUST"]; !redeemed {
+		t.Error("expected RUST to be currently redeemed")
+	}
+	if len(history["BASS"]) != 2 {
+		t.Fatalf("expected 2 history events for BASS, got %d", len(history["BASS"]))
+	}
+	if history["BASS"][1].Reason != "spoiled gift" {
+		t.Errorf("expected revoke reason to be preserved, got %q", history["BASS"][1].Reason)
+	}
+}
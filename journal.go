@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// JournalEntry is a single line written to the append-only redemption
+// journal. It mirrors RedemptionEvent in a form stable to serialize; Kind
+// defaults to "redeemed" when omitted so journal lines written before
+// revocation support remain valid.
+type JournalEntry struct {
+	TeamName  string `json:"team_name"`
+	Kind      string `json:"kind,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Journal is an append-only, fsync'd log of redemption events. Replaying it
+// on startup lets a RedemptionManager recover its in-memory state after a
+// crash, so a restarted process never forgets a redemption or revocation
+// that already happened.
+type Journal struct {
+	file *os.File
+}
+
+// OpenJournal opens (or creates) the journal file at path for appending.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open journal file: %w", err)
+	}
+	return &Journal{file: f}, nil
+}
+
+// Append writes entry to the journal as a single JSON line and fsyncs it to
+// disk before returning, so a crash immediately after Append cannot lose the
+// record.
+func (j *Journal) Append(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to marshal journal entry: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write journal entry: %w", err)
+	}
+	return j.file.Sync()
+}
+
+// Close flushes and closes the journal file.
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// JournalRedemptionStore is a RedemptionStore backed by an append-only
+// journal file. It is the original storage mechanism for RedemptionManager;
+// NewRedemptionManagerFromFile builds one under the hood.
+type JournalRedemptionStore struct {
+	path    string
+	journal *Journal
+}
+
+// NewJournalRedemptionStore opens (or creates) the journal file at path.
+func NewJournalRedemptionStore(path string) (*JournalRedemptionStore, error) {
+	journal, err := OpenJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalRedemptionStore{path: path, journal: journal}, nil
+}
+
+// Load implements RedemptionStore by replaying the journal file.
+func (s *JournalRedemptionStore) Load() ([]RedemptionEvent, error) {
+	return replayJournal(s.path)
+}
+
+// Append implements RedemptionStore by appending an entry to the journal.
+func (s *JournalRedemptionStore) Append(event RedemptionEvent) error {
+	return s.journal.Append(JournalEntry{
+		TeamName:  event.TeamName,
+		Kind:      string(event.Kind),
+		Timestamp: event.Timestamp,
+		Reason:    event.Reason,
+	})
+}
+
+// Close implements RedemptionStore by closing the journal file.
+func (s *JournalRedemptionStore) Close() error {
+	return s.journal.Close()
+}
+
+// replayJournal reads every entry from the journal file at path and returns
+// the resulting events in order. A missing file is treated as an empty
+// journal. Any malformed trailing line (for example one left half-written by
+// a crash) is skipped with a logged warning rather than failing the whole
+// replay.
+func replayJournal(path string) ([]RedemptionEvent, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open journal file: %w", err)
+	}
+	defer f.Close()
+
+	var events []RedemptionEvent
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			log.Printf("warning: skipping malformed journal line %d in %s: %v", lineNum, path, err)
+			continue
+		}
+		kind := RedemptionEventKind(entry.Kind)
+		if kind == "" {
+			kind = EventRedeemed
+		}
+		events = append(events, RedemptionEvent{
+			TeamName:  entry.TeamName,
+			Kind:      kind,
+			Timestamp: entry.Timestamp,
+			Reason:    entry.Reason,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading journal file: %w", err)
+	}
+	return events, nil
+}
@@ -1,11 +1,12 @@
 package main
 
 import (
-	"encoding/csv"
+	"bufio"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,53 +18,14 @@ type StaffMapping struct {
 	CreatedAt   int64
 }
 
-// LoadMappingFromCSV reads a CSV file at filePath and returns a slice of StaffMapping.
-// The CSV file must have headers: "staff_pass_id", "team_name", "created_at" (epoch milliseconds).
-func LoadMappingFromCSV(filePath string) ([]StaffMapping, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("unable to open CSV file: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("error reading CSV file: %w", err)
-	}
-
-	var mappings []StaffMapping
-	// Skip header row and iterate over each record.
-	for i, record := range records {
-		if i == 0 {
-			// Assuming first row is the header.
-			continue
-		}
-		if len(record) < 3 {
-			// Skip invalid records.
-			continue
-		}
-		createdAt, err := strconv.ParseInt(record[2], 10, 64)
-		if err != nil {
-			// Skip record if created_at is invalid.
-			continue
-		}
-		mapping := StaffMapping{
-			StaffPassID: record[0],
-			TeamName:    record[1],
-			CreatedAt:   createdAt,
-		}
-		mappings = append(mappings, mapping)
-	}
-
-	return mappings, nil
-}
-
-// BuildLookupMap converts a slice of StaffMapping into a map for quick lookup by staff pass ID.
-func BuildLookupMap(mappings []StaffMapping) map[string]string {
-	lookup := make(map[string]string)
+// BuildLookupMap converts a slice of StaffMapping into a map for quick
+// lookup by staff pass ID. The full StaffMapping is kept (not just the team
+// name) so callers can apply policies like MinCreatedAtPolicy that need the
+// pass's CreatedAt.
+func BuildLookupMap(mappings []StaffMapping) map[string]StaffMapping {
+	lookup := make(map[string]StaffMapping)
 	for _, mapping := range mappings {
-		lookup[mapping.StaffPassID] = mapping.TeamName
+		lookup[mapping.StaffPassID] = mapping
 	}
 	return lookup
 }
@@ -74,96 +36,317 @@ type Redemption struct {
 	RedeemedAt int64
 }
 
-// RedemptionManager manages redemption records and ensures a team can redeem only once.
+// RedemptionManager manages redemption records and ensures a team can redeem
+// only once at a time; a revoked redemption makes the team eligible again.
+// Eligibility beyond that is delegated to a Policy, defaulting to
+// OncePerTeamPolicy when none is configured.
 type RedemptionManager struct {
 	mu          sync.Mutex
 	redemptions map[string]Redemption
+	history     map[string][]RedemptionEvent
+	store       RedemptionStore
+	policy      Policy
 }
 
-// NewRedemptionManager initializes a new RedemptionManager.
+// NewRedemptionManager initializes a new RedemptionManager with no backing
+// store; its state lives in memory only and will not survive a restart.
 func NewRedemptionManager() *RedemptionManager {
 	return &RedemptionManager{
 		redemptions: make(map[string]Redemption),
+		history:     make(map[string][]RedemptionEvent),
 	}
 }
 
-// IsEligible returns true if the team has not redeemed their gift yet.
-func (rm *RedemptionManager) IsEligible(teamName string) bool {
+// NewRedemptionManagerFromFile initializes a RedemptionManager backed by an
+// append-only journal at path. Any redemptions already recorded in the
+// journal are replayed into memory first, so restarting the program with the
+// same journal file restores all prior redemptions and no team can
+// double-redeem across a crash.
+func NewRedemptionManagerFromFile(path string) (*RedemptionManager, error) {
+	store, err := NewJournalRedemptionStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedemptionManagerWithStore(store)
+}
+
+// NewRedemptionManagerWithStore initializes a RedemptionManager backed by an
+// arbitrary RedemptionStore, replaying its prior redemptions into memory
+// first. This lets the backend be swapped (journal file, SQLite, ...)
+// without changing any redemption logic.
+func NewRedemptionManagerWithStore(store RedemptionStore) (*RedemptionManager, error) {
+	return NewRedemptionManagerWithPolicy(store, OncePerTeamPolicy{})
+}
+
+// NewRedemptionManagerWithPolicy initializes a RedemptionManager backed by
+// store (which may be nil for in-memory-only state) and governed by policy
+// instead of the default OncePerTeamPolicy. This is how eligibility rules
+// like event time windows or pass-issuance cutoffs get wired in.
+func NewRedemptionManagerWithPolicy(store RedemptionStore, policy Policy) (*RedemptionManager, error) {
+	var events []RedemptionEvent
+	if store != nil {
+		var err error
+		events, err = store.Load()
+		if err != nil {
+			return nil, err
+		}
+	}
+	redemptions, history := foldEvents(events)
+	return &RedemptionManager{
+		redemptions: redemptions,
+		history:     history,
+		store:       store,
+		policy:      policy,
+	}, nil
+}
+
+// Close flushes and closes the underlying store, if this manager has one.
+func (rm *RedemptionManager) Close() error {
+	if rm.store == nil {
+		return nil
+	}
+	return rm.store.Close()
+}
+
+// List returns a snapshot of the currently active redemptions, one per team.
+// A revoked redemption is not included; History still holds its events.
+func (rm *RedemptionManager) List() []Redemption {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
-	_, exists := rm.redemptions[teamName]
-	return !exists
+	redemptions := make([]Redemption, 0, len(rm.redemptions))
+	for _, redemption := range rm.redemptions {
+		redemptions = append(redemptions, redemption)
+	}
+	return redemptions
 }
 
-// AddRedemption adds a redemption record for the team if they are eligible.
-// If the team has already redeemed, it returns an error.
-func (rm *RedemptionManager) AddRedemption(teamName string) (*Redemption, error) {
+// IsEligible returns true if teamName currently passes the manager's Policy.
+// passCreatedAt is the redeeming staff pass's CreatedAt (epoch milliseconds),
+// needed by policies like MinCreatedAtPolicy; omit it if unknown or
+// irrelevant to the configured policy.
+func (rm *RedemptionManager) IsEligible(teamName string, passCreatedAt ...int64) bool {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
+	return rm.checkPolicy(teamName, firstOrZero(passCreatedAt)) == nil
+}
 
-	if _, exists := rm.redemptions[teamName]; exists {
-		return nil, errors.New("team has already redeemed their gift")
+// AddRedemption adds a redemption record for the team if the manager's
+// Policy allows it right now; see IsEligible for passCreatedAt. If not
+// eligible, it returns the typed error the Policy reported (for example
+// ErrAlreadyRedeemed, ErrOutsideWindow, or ErrPassTooNew).
+func (rm *RedemptionManager) AddRedemption(teamName string, passCreatedAt ...int64) (*Redemption, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if err := rm.checkPolicy(teamName, firstOrZero(passCreatedAt)); err != nil {
+		return nil, err
 	}
 
 	redemption := Redemption{
 		TeamName:   teamName,
 		RedeemedAt: time.Now().UnixMilli(), // Requires Go 1.17 or later.
 	}
+	event := RedemptionEvent{TeamName: teamName, Kind: EventRedeemed, Timestamp: redemption.RedeemedAt}
+
+	if rm.store != nil {
+		if err := rm.store.Append(event); err != nil {
+			return nil, fmt.Errorf("unable to persist redemption: %w", err)
+		}
+	}
+
 	rm.redemptions[teamName] = redemption
+	rm.history[teamName] = append(rm.history[teamName], event)
 	return &redemption, nil
 }
 
+// checkPolicy evaluates the manager's Policy (OncePerTeamPolicy if none was
+// configured) for teamName. Callers must hold rm.mu.
+func (rm *RedemptionManager) checkPolicy(teamName string, passCreatedAt int64) error {
+	policy := rm.policy
+	if policy == nil {
+		policy = OncePerTeamPolicy{}
+	}
+	_, alreadyRedeemed := rm.redemptions[teamName]
+	return policy.Check(PolicyCheck{
+		TeamName:        teamName,
+		Now:             time.Now().UnixMilli(),
+		AlreadyRedeemed: alreadyRedeemed,
+		PassCreatedAt:   passCreatedAt,
+	})
+}
+
+// firstOrZero returns values[0], or 0 if values is empty. It backs the
+// variadic optional passCreatedAt parameter on IsEligible and AddRedemption.
+func firstOrZero(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[0]
+}
+
+// RevokeRedemption undoes a team's redemption, for example when a gift was
+// handed to the wrong person or turned out to be spoiled. It does not erase
+// the team's record; it appends a RedemptionEvent so the full timeline
+// remains available through History. A subsequent AddRedemption for the
+// same team is allowed after a revoke.
+func (rm *RedemptionManager) RevokeRedemption(teamName, reason string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if _, exists := rm.redemptions[teamName]; !exists {
+		return errors.New("team has not redeemed their gift")
+	}
+
+	event := RedemptionEvent{
+		TeamName:  teamName,
+		Kind:      EventRevoked,
+		Timestamp: time.Now().UnixMilli(),
+		Reason:    reason,
+	}
+
+	if rm.store != nil {
+		if err := rm.store.Append(event); err != nil {
+			return fmt.Errorf("unable to persist revocation: %w", err)
+		}
+	}
+
+	delete(rm.redemptions, teamName)
+	rm.history[teamName] = append(rm.history[teamName], event)
+	return nil
+}
+
+// History returns the full redemption timeline for teamName, in the order
+// the events happened.
+func (rm *RedemptionManager) History(teamName string) []RedemptionEvent {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	events := rm.history[teamName]
+	out := make([]RedemptionEvent, len(events))
+	copy(out, events)
+	return out
+}
+
 func main() {
-	// For demonstration, the program expects one command-line argument:
+	// "serve" runs the redemption booth as an HTTP API so multiple checkout
+	// stations can share one authoritative RedemptionManager concurrently.
+	if len(os.Args) >= 2 && os.Args[1] == "serve" {
+		runServer(os.Args[2:])
+		return
+	}
+
+	// Otherwise the program expects one required and one optional
+	// positional argument:
 	// 1. Path to the mapping CSV file.
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go <mapping_csv_file>")
+	// 2. Path to the redemption journal file (default "redemptions.journal"),
+	//    used only with the default -store=csv backend.
+	//
+	// The package is split across multiple files, so `go run main.go` will
+	// not compile; build the binary first or use `go run .`.
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	storeKind := fs.String("store", "csv", "storage backend: csv or sqlite")
+	dbFile := fs.String("db", "redemptions.db", "path to the SQLite database file (sqlite store only)")
+	windowStart := fs.String("window-start", "", "RFC 3339 timestamp before which redemptions are rejected (requires -window-end)")
+	windowEnd := fs.String("window-end", "", "RFC 3339 timestamp after which redemptions are rejected (requires -window-start)")
+	createdBefore := fs.String("created-before", "", "RFC 3339 timestamp; staff passes created at or after it are rejected")
+	fs.Parse(os.Args[1:])
+
+	args := fs.Args()
+	if len(args) < 1 {
+		fmt.Println("Usage: go run . [-store csv|sqlite] [-db <db_file>] [-window-start <rfc3339>] [-window-end <rfc3339>] [-created-before <rfc3339>] <mapping_csv_file> [journal_file]")
+		fmt.Println("       go run . serve -mapping <mapping_csv_file> [-journal <journal_file>] [-addr :8080]")
 		return
 	}
 
-	mappingFile := os.Args[1]
+	mappingFile := args[0]
+	journalFile := "redemptions.journal"
+	if len(args) >= 2 {
+		journalFile = args[1]
+	}
+
+	mappingStore, redemptionStore, err := buildStores(*storeKind, mappingFile, journalFile, *dbFile)
+	if err != nil {
+		fmt.Println("Error initializing storage backend:", err)
+		return
+	}
 
-	// Load mappings from the CSV file.
-	mappings, err := LoadMappingFromCSV(mappingFile)
+	mappings, err := mappingStore.Load()
 	if err != nil {
-		fmt.Println("Error loading mapping file:", err)
+		fmt.Println("Error loading mappings:", err)
 		return
 	}
 	lookup := BuildLookupMap(mappings)
 
-	// Initialize RedemptionManager.
-	redemptionManager := NewRedemptionManager()
+	policy, err := buildPolicy(*windowStart, *windowEnd, *createdBefore)
+	if err != nil {
+		fmt.Println("Error configuring eligibility policy:", err)
+		return
+	}
 
-	for {
-		// Prompt the user for a staff pass ID.
-		fmt.Print("Enter staff pass ID (or type 'exit' to quit): ")
-		var staffPassID string
-		fmt.Scanln(&staffPassID)
+	// Initialize RedemptionManager, recovering any prior redemptions from the
+	// store so a restart can't let a team redeem twice.
+	redemptionManager, err := NewRedemptionManagerWithPolicy(redemptionStore, policy)
+	if err != nil {
+		fmt.Println("Error initializing redemption manager:", err)
+		return
+	}
+	defer redemptionManager.Close()
 
-		// Check if the user wants to exit.
-		if staffPassID == "exit" {
-			fmt.Println("Exiting the program.")
+	stdin := bufio.NewScanner(os.Stdin)
+	for {
+		// Prompt the user for a staff pass ID, or a 'revoke'/'history' command.
+		fmt.Print("Enter staff pass ID, 'revoke <staff_pass_id> [reason]', 'history <team>', or 'exit': ")
+		if !stdin.Scan() {
 			break
 		}
-
-		// Lookup the team name for the given staff pass ID.
-		teamName, found := lookup[staffPassID]
-		if !found {
-			fmt.Println("Staff pass ID not found.")
+		fields := strings.Fields(stdin.Text())
+		if len(fields) == 0 {
 			continue
 		}
-		fmt.Println("Staff pass belongs to team:", teamName)
 
-		// Check if the team is eligible for redemption.
-		if redemptionManager.IsEligible(teamName) {
-			redemption, err := redemptionManager.AddRedemption(teamName)
+		switch fields[0] {
+		case "exit":
+			fmt.Println("Exiting the program.")
+			return
+		case "revoke":
+			if len(fields) < 2 {
+				fmt.Println("Usage: revoke <staff_pass_id> [reason]")
+				continue
+			}
+			mapping, found := lookup[fields[1]]
+			if !found {
+				fmt.Println("Staff pass ID not found.")
+				continue
+			}
+			reason := strings.Join(fields[2:], " ")
+			if err := redemptionManager.RevokeRedemption(mapping.TeamName, reason); err != nil {
+				fmt.Println("Error during revocation:", err)
+			} else {
+				fmt.Printf("Redemption revoked for team %s\n", mapping.TeamName)
+			}
+		case "history":
+			if len(fields) < 2 {
+				fmt.Println("Usage: history <team>")
+				continue
+			}
+			for _, event := range redemptionManager.History(fields[1]) {
+				fmt.Printf("%s %s at %d %s\n", fields[1], event.Kind, event.Timestamp, event.Reason)
+			}
+		default:
+			staffPassID := fields[0]
+			mapping, found := lookup[staffPassID]
+			if !found {
+				fmt.Println("Staff pass ID not found.")
+				continue
+			}
+			fmt.Println("Staff pass belongs to team:", mapping.TeamName)
+
+			redemption, err := redemptionManager.AddRedemption(mapping.TeamName, mapping.CreatedAt)
 			if err != nil {
-				fmt.Println("Error during redemption:", err)
+				fmt.Println("Redemption rejected:", err)
 			} else {
 				fmt.Printf("Redemption successful for team %s at timestamp %d\n", redemption.TeamName, redemption.RedeemedAt)
 			}
-		} else {
-			fmt.Println("Team has already redeemed their gift. Please send the representative away.")
 		}
 	}
 }
@@ -1,48 +1,11 @@
 package main
 
 import (
-    "os"
     "strings"
     "testing"
     "time"
 )
 
-// TestLoadMappingFromCSV creates a temporary CSV file, loads the data, and verifies correct parsing.
-func TestLoadMappingFromCSV(t *testing.T) {
-    // Prepare sample CSV data.
-    csvData := `staff_pass_id,team_name,created_at
-STAFF_H123804820G,BASS,1623772799000
-MANAGER_T999888420B,RUST,1623772799000
-BOSS_T000000001P,RUST,1623872111000
-`
-    // Write CSV data to a temporary file.
-    tmpFile, err := os.CreateTemp("", "test_mapping_*.csv")
-    if err != nil {
-        t.Fatalf("unable to create temp file: %v", err)
-    }
-    defer os.Remove(tmpFile.Name())
-    if _, err := tmpFile.WriteString(csvData); err != nil {
-        t.Fatalf("unable to write to temp file: %v", err)
-    }
-    tmpFile.Close()
-
-    // Load mappings.
-    mappings, err := LoadMappingFromCSV(tmpFile.Name())
-    if err != nil {
-        t.Fatalf("expected no error, got %v", err)
-    }
-
-    // Expect 3 mappings.
-    if len(mappings) != 3 {
-        t.Errorf("expected 3 mappings, got %d", len(mappings))
-    }
-
-    // Check first mapping.
-    if mappings[0].StaffPassID != "STAFF_H123804820G" || mappings[0].TeamName != "BASS" {
-        t.Errorf("unexpected mapping record: %+v", mappings[0])
-    }
-}
-
 // TestBuildLookupMap verifies that a lookup map is correctly built from mappings.
 func TestBuildLookupMap(t *testing.T) {
     mappings := []StaffMapping{
@@ -50,11 +13,11 @@ func TestBuildLookupMap(t *testing.T) {
         {StaffPassID: "ID2", TeamName: "TeamB", CreatedAt: 2000},
     }
     lookup := BuildLookupMap(mappings)
-    if team, ok := lookup["ID1"]; !ok || team != "TeamA" {
-        t.Errorf("expected ID1 to map to TeamA, got %v", team)
+    if mapping, ok := lookup["ID1"]; !ok || mapping.TeamName != "TeamA" {
+        t.Errorf("expected ID1 to map to TeamA, got %v", mapping)
     }
-    if team, ok := lookup["ID2"]; !ok || team != "TeamB" {
-        t.Errorf("expected ID2 to map to TeamB, got %v", team)
+    if mapping, ok := lookup["ID2"]; !ok || mapping.TeamName != "TeamB" {
+        t.Errorf("expected ID2 to map to TeamB, got %v", mapping)
     }
 }
 
@@ -92,3 +55,45 @@ func TestRedemptionManager(t *testing.T) {
         t.Errorf("expected error about team already redeemed, got %v", err)
     }
 }
+
+// TestRevokeRedemption verifies that revoking a redemption makes the team
+// eligible again and records both events in its history.
+func TestRevokeRedemption(t *testing.T) {
+    rm := NewRedemptionManager()
+    teamName := "TeamA"
+
+    if _, err := rm.AddRedemption(teamName); err != nil {
+        t.Fatalf("unable to add redemption: %v", err)
+    }
+
+    if err := rm.RevokeRedemption(teamName, "handed to wrong team"); err != nil {
+        t.Fatalf("unable to revoke redemption: %v", err)
+    }
+    if !rm.IsEligible(teamName) {
+        t.Errorf("expected team to be eligible again after revoke")
+    }
+
+    if _, err := rm.AddRedemption(teamName); err != nil {
+        t.Fatalf("expected redemption to succeed after revoke, got error: %v", err)
+    }
+
+    history := rm.History(teamName)
+    if len(history) != 3 {
+        t.Fatalf("expected 3 history events, got %d: %+v", len(history), history)
+    }
+    if history[0].Kind != EventRedeemed || history[1].Kind != EventRevoked || history[2].Kind != EventRedeemed {
+        t.Errorf("unexpected history kinds: %+v", history)
+    }
+    if history[1].Reason != "handed to wrong team" {
+        t.Errorf("expected revoke reason to be recorded, got %q", history[1].Reason)
+    }
+}
+
+// TestRevokeRedemptionNotRedeemed verifies that revoking a team that never
+// redeemed returns an error.
+func TestRevokeRedemptionNotRedeemed(t *testing.T) {
+    rm := NewRedemptionManager()
+    if err := rm.RevokeRedemption("TeamA", "no reason"); err == nil {
+        t.Error("expected an error when revoking a team that has not redeemed")
+    }
+}
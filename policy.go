@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by Policy implementations and passed on by AddRedemption,
+// so callers (the CLI, the HTTP API) can distinguish why a redemption attempt
+// was rejected. IsEligible reports only a bool.
+var (
+	ErrAlreadyRedeemed = errors.New("team has already redeemed their gift")
+	ErrOutsideWindow   = errors.New("redemption attempted outside the event time window")
+	ErrPassTooNew      = errors.New("staff pass was issued after the eligibility cutoff")
+)
+
+// PolicyCheck carries everything a Policy needs to decide whether a
+// redemption attempt is currently eligible.
+type PolicyCheck struct {
+	TeamName        string
+	Now             int64 // epoch milliseconds
+	AlreadyRedeemed bool
+	PassCreatedAt   int64 // epoch milliseconds; zero if the caller didn't supply one
+}
+
+// Policy decides whether a redemption attempt is eligible. It returns nil if
+// so, or a typed error (one of the Err* vars above, or a custom one) if not.
+type Policy interface {
+	Check(PolicyCheck) error
+}
+
+// OncePerTeamPolicy is the original eligibility rule: a team may redeem only
+// if it has not already done so.
+type OncePerTeamPolicy struct{}
+
+// Check implements Policy.
+func (OncePerTeamPolicy) Check(ctx PolicyCheck) error {
+	if ctx.AlreadyRedeemed {
+		return ErrAlreadyRedeemed
+	}
+	return nil
+}
+
+// TimeWindowPolicy rejects redemptions attempted outside [Start, End],
+// useful for restricting redemption to event hours.
+type TimeWindowPolicy struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Check implements Policy.
+func (p TimeWindowPolicy) Check(ctx PolicyCheck) error {
+	now := time.UnixMilli(ctx.Now)
+	if now.Before(p.Start) || now.After(p.End) {
+		return ErrOutsideWindow
+	}
+	return nil
+}
+
+// MinCreatedAtPolicy rejects a redemption if the staff pass was created at
+// or after Cutoff, excluding passes issued after the event started. A
+// PassCreatedAt of zero (not supplied by the caller) is treated as unknown
+// and passes this check.
+type MinCreatedAtPolicy struct {
+	Cutoff time.Time
+}
+
+// Check implements Policy.
+func (p MinCreatedAtPolicy) Check(ctx PolicyCheck) error {
+	if ctx.PassCreatedAt != 0 && ctx.PassCreatedAt >= p.Cutoff.UnixMilli() {
+		return ErrPassTooNew
+	}
+	return nil
+}
+
+// CompositePolicy ANDs several policies together, returning the first
+// rejection encountered.
+type CompositePolicy []Policy
+
+// Check implements Policy.
+func (c CompositePolicy) Check(ctx PolicyCheck) error {
+	for _, policy := range c {
+		if err := policy.Check(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ Policy = OncePerTeamPolicy{}
+var _ Policy = TimeWindowPolicy{}
+var _ Policy = MinCreatedAtPolicy{}
+var _ Policy = CompositePolicy{}
+
+// buildPolicy always applies OncePerTeamPolicy, plus a TimeWindowPolicy if
+// both windowStart and windowEnd are set, plus a MinCreatedAtPolicy if
+// createdBefore is set. Each time string must be RFC 3339
+// (e.g. "2026-07-27T09:00:00Z"). windowStart/windowEnd/createdBefore left
+// empty are simply not applied. Shared by the interactive CLI and the serve
+// subcommand so both expose the same eligibility flags.
+func buildPolicy(windowStart, windowEnd, createdBefore string) (Policy, error) {
+	policies := CompositePolicy{OncePerTeamPolicy{}}
+
+	if windowStart != "" || windowEnd != "" {
+		if windowStart == "" || windowEnd == "" {
+			return nil, errors.New("-window-start and -window-end must both be set together")
+		}
+		start, err := time.Parse(time.RFC3339, windowStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -window-start: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, windowEnd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -window-end: %w", err)
+		}
+		policies = append(policies, TimeWindowPolicy{Start: start, End: end})
+	}
+
+	if createdBefore != "" {
+		cutoff, err := time.Parse(time.RFC3339, createdBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -created-before: %w", err)
+		}
+		policies = append(policies, MinCreatedAtPolicy{Cutoff: cutoff})
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOncePerTeamPolicy verifies the default once-only eligibility rule.
+func TestOncePerTeamPolicy(t *testing.T) {
+	policy := OncePerTeamPolicy{}
+
+	if err := policy.Check(PolicyCheck{TeamName: "TeamA", AlreadyRedeemed: false}); err != nil {
+		t.Errorf("expected no error for a team that has not redeemed, got %v", err)
+	}
+
+	err := policy.Check(PolicyCheck{TeamName: "TeamA", AlreadyRedeemed: true})
+	if !errors.Is(err, ErrAlreadyRedeemed) {
+		t.Errorf("expected ErrAlreadyRedeemed, got %v", err)
+	}
+}
+
+// TestTimeWindowPolicy verifies that redemptions are only allowed inside
+// [Start, End].
+func TestTimeWindowPolicy(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 1, 17, 0, 0, 0, time.UTC)
+	policy := TimeWindowPolicy{Start: start, End: end}
+
+	inside := start.Add(time.Hour).UnixMilli()
+	if err := policy.Check(PolicyCheck{Now: inside}); err != nil {
+		t.Errorf("expected no error for a time inside the window, got %v", err)
+	}
+
+	before := start.Add(-time.Minute).UnixMilli()
+	if err := policy.Check(PolicyCheck{Now: before}); !errors.Is(err, ErrOutsideWindow) {
+		t.Errorf("expected ErrOutsideWindow before the window, got %v", err)
+	}
+
+	after := end.Add(time.Minute).UnixMilli()
+	if err := policy.Check(PolicyCheck{Now: after}); !errors.Is(err, ErrOutsideWindow) {
+		t.Errorf("expected ErrOutsideWindow after the window, got %v", err)
+	}
+}
+
+// TestMinCreatedAtPolicy verifies that passes issued at or after the cutoff
+// are rejected, while an unsupplied (zero) PassCreatedAt always passes.
+func TestMinCreatedAtPolicy(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := MinCreatedAtPolicy{Cutoff: cutoff}
+
+	early := cutoff.Add(-time.Hour).UnixMilli()
+	if err := policy.Check(PolicyCheck{PassCreatedAt: early}); err != nil {
+		t.Errorf("expected no error for a pass issued before the cutoff, got %v", err)
+	}
+
+	late := cutoff.Add(time.Hour).UnixMilli()
+	if err := policy.Check(PolicyCheck{PassCreatedAt: late}); !errors.Is(err, ErrPassTooNew) {
+		t.Errorf("expected ErrPassTooNew for a pass issued after the cutoff, got %v", err)
+	}
+
+	if err := policy.Check(PolicyCheck{PassCreatedAt: 0}); err != nil {
+		t.Errorf("expected no error when PassCreatedAt is unsupplied, got %v", err)
+	}
+}
+
+// TestBuildPolicy verifies that buildPolicy always applies
+// OncePerTeamPolicy, adds TimeWindowPolicy/MinCreatedAtPolicy only when their
+// flags are set, and rejects a lone -window-start or -window-end.
+func TestBuildPolicy(t *testing.T) {
+	policy, err := buildPolicy("", "", "")
+	if err != nil {
+		t.Fatalf("unable to build default policy: %v", err)
+	}
+	if err := policy.Check(PolicyCheck{AlreadyRedeemed: true}); !errors.Is(err, ErrAlreadyRedeemed) {
+		t.Errorf("expected OncePerTeamPolicy to always be applied, got %v", err)
+	}
+
+	policy, err = buildPolicy("2026-01-01T09:00:00Z", "2026-01-01T17:00:00Z", "")
+	if err != nil {
+		t.Fatalf("unable to build a policy with a time window: %v", err)
+	}
+	outsideWindow, _ := time.Parse(time.RFC3339, "2026-01-01T20:00:00Z")
+	if err := policy.Check(PolicyCheck{Now: outsideWindow.UnixMilli()}); !errors.Is(err, ErrOutsideWindow) {
+		t.Errorf("expected ErrOutsideWindow outside the configured window, got %v", err)
+	}
+
+	policy, err = buildPolicy("", "", "2026-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("unable to build a policy with a created-before cutoff: %v", err)
+	}
+	tooNew, _ := time.Parse(time.RFC3339, "2026-01-02T00:00:00Z")
+	if err := policy.Check(PolicyCheck{PassCreatedAt: tooNew.UnixMilli()}); !errors.Is(err, ErrPassTooNew) {
+		t.Errorf("expected ErrPassTooNew for a pass issued after the cutoff, got %v", err)
+	}
+
+	if _, err := buildPolicy("2026-01-01T09:00:00Z", "", ""); err == nil {
+		t.Error("expected an error when only -window-start is set")
+	}
+	if _, err := buildPolicy("2026-01-01T09:00:00Z", "not-a-timestamp", ""); err == nil {
+		t.Error("expected an error for an invalid -window-end")
+	}
+}
+
+// TestCompositePolicy verifies that all policies must pass, and that the
+// first rejection is returned.
+func TestCompositePolicy(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	composite := CompositePolicy{
+		OncePerTeamPolicy{},
+		MinCreatedAtPolicy{Cutoff: cutoff},
+	}
+
+	ok := PolicyCheck{
+		TeamName:        "TeamA",
+		AlreadyRedeemed: false,
+		PassCreatedAt:   cutoff.Add(-time.Hour).UnixMilli(),
+	}
+	if err := composite.Check(ok); err != nil {
+		t.Errorf("expected no error when every policy passes, got %v", err)
+	}
+
+	alreadyRedeemed := ok
+	alreadyRedeemed.AlreadyRedeemed = true
+	if err := composite.Check(alreadyRedeemed); !errors.Is(err, ErrAlreadyRedeemed) {
+		t.Errorf("expected ErrAlreadyRedeemed to short-circuit the composite, got %v", err)
+	}
+
+	tooNew := ok
+	tooNew.PassCreatedAt = cutoff.Add(time.Hour).UnixMilli()
+	if err := composite.Check(tooNew); !errors.Is(err, ErrPassTooNew) {
+		t.Errorf("expected ErrPassTooNew from the second policy, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Server exposes RedemptionManager operations over HTTP so multiple
+// checkout stations can hit one authoritative process concurrently. The
+// underlying RedemptionManager already guards its state with a mutex, so no
+// additional locking is needed here.
+type Server struct {
+	lookup  map[string]StaffMapping
+	manager *RedemptionManager
+}
+
+// NewServer creates a Server for the given staff pass lookup and
+// RedemptionManager.
+func NewServer(lookup map[string]StaffMapping, manager *RedemptionManager) *Server {
+	return &Server{lookup: lookup, manager: manager}
+}
+
+// Routes returns the HTTP handler exposing the redemption API:
+//
+//	GET  /teams/{staff_pass_id}  resolve a pass to a team
+//	POST /redemptions            attempt a redemption
+//	GET  /redemptions            list active (non-revoked) redemptions
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/teams/", s.handleGetTeam)
+	mux.HandleFunc("/redemptions", s.handleRedemptions)
+	return mux
+}
+
+type teamResponse struct {
+	StaffPassID string `json:"staff_pass_id"`
+	TeamName    string `json:"team_name"`
+}
+
+// handleGetTeam handles GET /teams/{staff_pass_id}.
+func (s *Server) handleGetTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	staffPassID := strings.TrimPrefix(r.URL.Path, "/teams/")
+	mapping, found := s.lookup[staffPassID]
+	if !found {
+		http.Error(w, "staff pass ID not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, teamResponse{StaffPassID: staffPassID, TeamName: mapping.TeamName})
+}
+
+// handleRedemptions dispatches POST /redemptions and GET /redemptions.
+func (s *Server) handleRedemptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.handlePostRedemption(w, r)
+	case http.MethodGet:
+		s.handleListRedemptions(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type redeemRequest struct {
+	StaffPassID string `json:"staff_pass_id"`
+}
+
+// handlePostRedemption handles POST /redemptions with a body of
+// {"staff_pass_id": "..."}, returning the redemption record on success, 404
+// if the pass is unknown, 409 if the team already redeemed, and 403 if a
+// time-window or pass-issuance policy rejected the attempt.
+func (s *Server) handlePostRedemption(w http.ResponseWriter, r *http.Request) {
+	var req redeemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mapping, found := s.lookup[req.StaffPassID]
+	if !found {
+		http.Error(w, "staff pass ID not found", http.StatusNotFound)
+		return
+	}
+
+	redemption, err := s.manager.AddRedemption(mapping.TeamName, mapping.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAlreadyRedeemed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, ErrOutsideWindow), errors.Is(err, ErrPassTooNew):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, redemption)
+}
+
+// handleListRedemptions handles GET /redemptions.
+func (s *Server) handleListRedemptions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.List())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// runServer parses serve-subcommand flags and runs the HTTP API until the
+// process is killed.
+func runServer(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	mappingFile := fs.String("mapping", "", "path to the mapping CSV file (csv store), or a CSV file to seed the database with (sqlite store)")
+	journalFile := fs.String("journal", "redemptions.journal", "path to the redemption journal file (csv store only)")
+	dbFile := fs.String("db", "redemptions.db", "path to the SQLite database file (sqlite store only)")
+	storeKind := fs.String("store", "csv", "storage backend: csv or sqlite")
+	addr := fs.String("addr", ":8080", "address to listen on")
+	windowStart := fs.String("window-start", "", "RFC 3339 timestamp before which redemptions are rejected (requires -window-end)")
+	windowEnd := fs.String("window-end", "", "RFC 3339 timestamp after which redemptions are rejected (requires -window-start)")
+	createdBefore := fs.String("created-before", "", "RFC 3339 timestamp; staff passes created at or after it are rejected")
+	fs.Parse(args)
+
+	if *storeKind == "csv" && *mappingFile == "" {
+		fmt.Println("Usage: go run . serve -mapping <mapping_csv_file> [-journal <journal_file>] [-addr :8080] [-window-start <rfc3339>] [-window-end <rfc3339>] [-created-before <rfc3339>]")
+		return
+	}
+
+	mappingStore, redemptionStore, err := buildStores(*storeKind, *mappingFile, *journalFile, *dbFile)
+	if err != nil {
+		fmt.Println("Error initializing storage backend:", err)
+		return
+	}
+
+	mappings, err := mappingStore.Load()
+	if err != nil {
+		fmt.Println("Error loading mappings:", err)
+		return
+	}
+	lookup := BuildLookupMap(mappings)
+
+	policy, err := buildPolicy(*windowStart, *windowEnd, *createdBefore)
+	if err != nil {
+		fmt.Println("Error configuring eligibility policy:", err)
+		return
+	}
+
+	redemptionManager, err := NewRedemptionManagerWithPolicy(redemptionStore, policy)
+	if err != nil {
+		fmt.Println("Error initializing redemption manager:", err)
+		return
+	}
+	defer redemptionManager.Close()
+
+	server := NewServer(lookup, redemptionManager)
+	fmt.Println("Listening on", *addr)
+	if err := http.ListenAndServe(*addr, server.Routes()); err != nil {
+		fmt.Println("Server error:", err)
+	}
+}
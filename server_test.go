@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *RedemptionManager) {
+	t.Helper()
+
+	lookup := map[string]StaffMapping{
+		"STAFF_H123804820G":   {StaffPassID: "STAFF_H123804820G", TeamName: "BASS", CreatedAt: 1000},
+		"MANAGER_T999888420B": {StaffPassID: "MANAGER_T999888420B", TeamName: "RUST", CreatedAt: 1000},
+	}
+	rm, err := NewRedemptionManagerFromFile(filepath.Join(t.TempDir(), "redemptions.journal"))
+	if err != nil {
+		t.Fatalf("unable to create redemption manager: %v", err)
+	}
+	t.Cleanup(func() { rm.Close() })
+
+	server := NewServer(lookup, rm)
+	ts := httptest.NewServer(server.Routes())
+	t.Cleanup(ts.Close)
+	return ts, rm
+}
+
+// TestHandleGetTeam verifies that GET /teams/{staff_pass_id} resolves a known
+// pass and 404s on an unknown one.
+func TestHandleGetTeam(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/teams/STAFF_H123804820G")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var team teamResponse
+	if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	if team.TeamName != "BASS" {
+		t.Errorf("expected team BASS, got %s", team.TeamName)
+	}
+
+	resp2, err := http.Get(ts.URL + "/teams/UNKNOWN")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp2.StatusCode)
+	}
+}
+
+// TestHandlePostRedemption verifies that a redemption succeeds once and is
+// rejected with 409 on a repeat attempt.
+func TestHandlePostRedemption(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	body, _ := json.Marshal(redeemRequest{StaffPassID: "STAFF_H123804820G"})
+	resp, err := http.Post(ts.URL+"/redemptions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp2, err := http.Post(ts.URL+"/redemptions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp2.StatusCode)
+	}
+}
+
+// TestHandleListRedemptions verifies that GET /redemptions reflects prior
+// redemptions.
+func TestHandleListRedemptions(t *testing.T) {
+	ts, rm := newTestServer(t)
+
+	if _, err := rm.AddRedemption("BASS"); err != nil {
+		t.Fatalf("unable to add redemption: %v", err)
+	}
+
+	resp, err := http.Get(ts.URL + "/redemptions")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var redemptions []Redemption
+	if err := json.NewDecoder(resp.Body).Decode(&redemptions); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	if len(redemptions) != 1 || redemptions[0].TeamName != "BASS" {
+		t.Errorf("expected one redemption for BASS, got %+v", redemptions)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite" // CGO-free SQLite driver
+)
+
+// openSQLiteDB opens (creating if necessary) a SQLite database at path and
+// ensures the mappings, redemption_events, and current_redemptions tables
+// exist.
+func openSQLiteDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite database: %w", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS mappings (
+			staff_pass_id TEXT PRIMARY KEY,
+			team_name     TEXT NOT NULL,
+			created_at    INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS redemption_events (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			team_name   TEXT NOT NULL,
+			kind        TEXT NOT NULL,
+			occurred_at INTEGER NOT NULL,
+			reason      TEXT NOT NULL DEFAULT ''
+		);
+		CREATE TABLE IF NOT EXISTS current_redemptions (
+			team_name TEXT PRIMARY KEY
+		);
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to create sqlite schema: %w", err)
+	}
+	return db, nil
+}
+
+// NewSQLiteStores opens a single SQLite database at path and returns a
+// MappingStore and RedemptionStore sharing its connection. redemption_events
+// is append-only and ordered by its autoincrement id, so a team's current
+// state is always the fold of its rows in insertion order. The
+// once-per-team-until-revoked invariant is additionally enforced at the DB
+// layer by current_redemptions, a derived table with team_name as its
+// PRIMARY KEY: Append inserts into it alongside the event row, in the same
+// transaction, so two processes racing to redeem the same team against this
+// database cannot both succeed, even though each only checks its own
+// in-memory RedemptionManager state first. A revoke deletes the team's row,
+// which is what allows it to legitimately redeem again later.
+func NewSQLiteStores(path string) (*SQLiteMappingStore, *SQLiteRedemptionStore, error) {
+	db, err := openSQLiteDB(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SQLiteMappingStore{db: db}, &SQLiteRedemptionStore{db: db}, nil
+}
+
+// SQLiteMappingStore is a MappingStore backed by a SQLite "mappings" table.
+type SQLiteMappingStore struct {
+	db *sql.DB
+}
+
+// Load implements MappingStore.
+func (s *SQLiteMappingStore) Load() ([]StaffMapping, error) {
+	rows, err := s.db.Query(`SELECT staff_pass_id, team_name, created_at FROM mappings`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []StaffMapping
+	for rows.Next() {
+		var m StaffMapping
+		if err := rows.Scan(&m.StaffPassID, &m.TeamName, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("unable to scan mapping row: %w", err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, rows.Err()
+}
+
+// Seed replaces the contents of the mappings table with the given mappings.
+// It is used to import a mapping CSV file into SQLite via the
+// `serve -store=sqlite -mapping <file>` flags.
+func (s *SQLiteMappingStore) Seed(mappings []StaffMapping) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM mappings`); err != nil {
+		return fmt.Errorf("unable to clear mappings: %w", err)
+	}
+	for _, m := range mappings {
+		if _, err := tx.Exec(
+			`INSERT INTO mappings (staff_pass_id, team_name, created_at) VALUES (?, ?, ?)`,
+			m.StaffPassID, m.TeamName, m.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("unable to insert mapping %s: %w", m.StaffPassID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SQLiteRedemptionStore is a RedemptionStore backed by a SQLite
+// "redemption_events" table.
+type SQLiteRedemptionStore struct {
+	db *sql.DB
+}
+
+// Load implements RedemptionStore, returning events in the order they were
+// appended.
+func (s *SQLiteRedemptionStore) Load() ([]RedemptionEvent, error) {
+	rows, err := s.db.Query(`SELECT team_name, kind, occurred_at, reason FROM redemption_events ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query redemption events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []RedemptionEvent
+	for rows.Next() {
+		var (
+			event RedemptionEvent
+			kind  string
+		)
+		if err := rows.Scan(&event.TeamName, &kind, &event.Timestamp, &event.Reason); err != nil {
+			return nil, fmt.Errorf("unable to scan redemption event row: %w", err)
+		}
+		event.Kind = RedemptionEventKind(kind)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// Append implements RedemptionStore by inserting a new event row. For a
+// redeem event, it first inserts the team into current_redemptions in the
+// same transaction; that table's PRIMARY KEY on team_name makes the
+// once-per-team-until-revoked invariant atomic across every process sharing
+// this database, not just within this one's in-memory RedemptionManager. A
+// revoke event deletes the team's current_redemptions row instead, clearing
+// the way for a later redemption.
+func (s *SQLiteRedemptionStore) Append(event RedemptionEvent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	switch event.Kind {
+	case EventRedeemed:
+		if _, err := tx.Exec(`INSERT INTO current_redemptions (team_name) VALUES (?)`, event.TeamName); err != nil {
+			if isUniqueConstraintError(err) {
+				return ErrAlreadyRedeemed
+			}
+			return fmt.Errorf("unable to record current redemption: %w", err)
+		}
+	case EventRevoked:
+		if _, err := tx.Exec(`DELETE FROM current_redemptions WHERE team_name = ?`, event.TeamName); err != nil {
+			return fmt.Errorf("unable to clear current redemption: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO redemption_events (team_name, kind, occurred_at, reason) VALUES (?, ?, ?, ?)`,
+		event.TeamName, string(event.Kind), event.Timestamp, event.Reason,
+	); err != nil {
+		return fmt.Errorf("unable to append redemption event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit redemption event: %w", err)
+	}
+	return nil
+}
+
+// isUniqueConstraintError reports whether err came from violating a SQLite
+// UNIQUE (or PRIMARY KEY) constraint, e.g. a second process racing to insert
+// the same team_name into current_redemptions.
+func isUniqueConstraintError(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// Close implements RedemptionStore by closing the underlying database
+// handle, which is shared with the SQLiteMappingStore returned alongside it.
+func (s *SQLiteRedemptionStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestSQLiteMappingStoreSeedAndLoad verifies that Seed replaces the
+// mappings table's contents and Load reads back what was written.
+func TestSQLiteMappingStoreSeedAndLoad(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	mappingStore, redemptionStore, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores: %v", err)
+	}
+	defer redemptionStore.Close()
+
+	mappings := []StaffMapping{
+		{StaffPassID: "ID1", TeamName: "TeamA", CreatedAt: 1000},
+		{StaffPassID: "ID2", TeamName: "TeamB", CreatedAt: 2000},
+	}
+	if err := mappingStore.Seed(mappings); err != nil {
+		t.Fatalf("unable to seed mappings: %v", err)
+	}
+
+	loaded, err := mappingStore.Load()
+	if err != nil {
+		t.Fatalf("unable to load mappings: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 mappings, got %d: %+v", len(loaded), loaded)
+	}
+
+	// Re-seeding should replace, not append to, the prior rows.
+	if err := mappingStore.Seed(mappings[:1]); err != nil {
+		t.Fatalf("unable to re-seed mappings: %v", err)
+	}
+	reloaded, err := mappingStore.Load()
+	if err != nil {
+		t.Fatalf("unable to reload mappings: %v", err)
+	}
+	if len(reloaded) != 1 {
+		t.Errorf("expected re-seed to replace mappings, got %d: %+v", len(reloaded), reloaded)
+	}
+}
+
+// TestSQLiteRedemptionStoreAppendAndLoad verifies that Append persists
+// events in order and Load replays them faithfully.
+func TestSQLiteRedemptionStoreAppendAndLoad(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	_, redemptionStore, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores: %v", err)
+	}
+	defer redemptionStore.Close()
+
+	events := []RedemptionEvent{
+		{TeamName: "BASS", Kind: EventRedeemed, Timestamp: 1000},
+		{TeamName: "BASS", Kind: EventRevoked, Timestamp: 2000, Reason: "handed to wrong team"},
+		{TeamName: "BASS", Kind: EventRedeemed, Timestamp: 3000},
+	}
+	for _, event := range events {
+		if err := redemptionStore.Append(event); err != nil {
+			t.Fatalf("unable to append event: %v", err)
+		}
+	}
+
+	loaded, err := redemptionStore.Load()
+	if err != nil {
+		t.Fatalf("unable to load events: %v", err)
+	}
+	if len(loaded) != len(events) {
+		t.Fatalf("expected %d events, got %d: %+v", len(events), len(loaded), loaded)
+	}
+	for i, want := range events {
+		if loaded[i] != want {
+			t.Errorf("event %d: expected %+v, got %+v", i, want, loaded[i])
+		}
+	}
+}
+
+// TestNewRedemptionManagerWithSQLiteStoreRecoversState verifies that a
+// RedemptionManager backed by a SQLiteRedemptionStore survives a restart,
+// the same guarantee JournalRedemptionStore provides.
+func TestNewRedemptionManagerWithSQLiteStoreRecoversState(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	_, redemptionStore, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores: %v", err)
+	}
+	rm, err := NewRedemptionManagerWithStore(redemptionStore)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager: %v", err)
+	}
+	if _, err := rm.AddRedemption("BASS"); err != nil {
+		t.Fatalf("unable to add redemption: %v", err)
+	}
+	if err := rm.Close(); err != nil {
+		t.Fatalf("unable to close redemption manager: %v", err)
+	}
+
+	_, reopenedStore, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to reopen sqlite stores: %v", err)
+	}
+	restarted, err := NewRedemptionManagerWithStore(reopenedStore)
+	if err != nil {
+		t.Fatalf("unable to recreate redemption manager: %v", err)
+	}
+	defer restarted.Close()
+
+	if restarted.IsEligible("BASS") {
+		t.Errorf("expected BASS to already be redeemed after restart")
+	}
+}
+
+// TestSQLiteRedemptionStoreEnforcesOncePerTeamAcrossProcesses verifies that
+// the once-per-team invariant holds at the database layer, not just within
+// one process's in-memory RedemptionManager: two RedemptionManagers opened
+// against the same SQLite file (simulating two checkout stations) must not
+// both be able to redeem the same team.
+func TestSQLiteRedemptionStoreEnforcesOncePerTeamAcrossProcesses(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	_, store1, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores for station 1: %v", err)
+	}
+	rm1, err := NewRedemptionManagerWithStore(store1)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager 1: %v", err)
+	}
+	defer rm1.Close()
+
+	_, store2, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores for station 2: %v", err)
+	}
+	rm2, err := NewRedemptionManagerWithStore(store2)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager 2: %v", err)
+	}
+	defer rm2.Close()
+
+	if _, err := rm1.AddRedemption("BASS"); err != nil {
+		t.Fatalf("expected station 1's redemption to succeed, got error: %v", err)
+	}
+
+	// Station 2 never saw station 1's redemption in its own in-memory state,
+	// but the shared database must still reject it.
+	if _, err := rm2.AddRedemption("BASS"); !errors.Is(err, ErrAlreadyRedeemed) {
+		t.Errorf("expected station 2's redemption to fail with ErrAlreadyRedeemed, got %v", err)
+	}
+}
+
+// TestSQLiteRedemptionStoreAllowsRedeemAfterRevokeAcrossProcesses verifies
+// that a revoke clears the DB-level constraint too, so a different process
+// can legitimately redeem again afterward.
+func TestSQLiteRedemptionStoreAllowsRedeemAfterRevokeAcrossProcesses(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	_, store1, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores for station 1: %v", err)
+	}
+	rm1, err := NewRedemptionManagerWithStore(store1)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager 1: %v", err)
+	}
+	defer rm1.Close()
+
+	if _, err := rm1.AddRedemption("BASS"); err != nil {
+		t.Fatalf("unable to add redemption: %v", err)
+	}
+	if err := rm1.RevokeRedemption("BASS", "handed to wrong team"); err != nil {
+		t.Fatalf("unable to revoke redemption: %v", err)
+	}
+
+	_, store2, err := NewSQLiteStores(dbPath)
+	if err != nil {
+		t.Fatalf("unable to open sqlite stores for station 2: %v", err)
+	}
+	rm2, err := NewRedemptionManagerWithStore(store2)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager 2: %v", err)
+	}
+	defer rm2.Close()
+
+	if _, err := rm2.AddRedemption("BASS"); err != nil {
+		t.Errorf("expected station 2's redemption to succeed after revoke, got error: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import "fmt"
+
+// MappingStore loads staff-pass-to-team mappings from a backing source.
+// LoadMappingFromCSV's file-based loader is just one implementation; a
+// SQLite-backed implementation lives in sqlite_store.go.
+type MappingStore interface {
+	Load() ([]StaffMapping, error)
+}
+
+// RedemptionStore persists a team's redemption timeline (redemptions and
+// revocations) so a RedemptionManager's state can be recovered after a
+// restart and, for backends like SQLite, shared across multiple processes.
+type RedemptionStore interface {
+	// Load returns every event recorded so far, in the order they happened.
+	Load() ([]RedemptionEvent, error)
+	// Append persists a new event.
+	Append(RedemptionEvent) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// CSVMappingStore is a MappingStore backed by a mapping CSV file.
+type CSVMappingStore struct {
+	FilePath string
+}
+
+// Load implements MappingStore. Rows rejected while parsing the CSV file are
+// logged as warnings rather than failing the whole load.
+func (s CSVMappingStore) Load() ([]StaffMapping, error) {
+	mappings, rowErrors, err := LoadMappingFromCSV(s.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	logRowErrors(rowErrors)
+	return mappings, nil
+}
+
+var (
+	_ MappingStore = CSVMappingStore{}
+	_ MappingStore = (*SQLiteMappingStore)(nil)
+
+	_ RedemptionStore = (*JournalRedemptionStore)(nil)
+	_ RedemptionStore = (*SQLiteRedemptionStore)(nil)
+)
+
+// buildStores constructs the MappingStore and RedemptionStore for storeKind
+// ("csv" or "sqlite"), shared by both the interactive CLI and the serve
+// subcommand so the -store flag behaves identically in each. For the sqlite
+// backend, mappingFile is optional: when set, it is used to (re)seed the
+// mappings table from a CSV file; when empty, the database's existing
+// mappings are used as-is.
+func buildStores(storeKind, mappingFile, journalFile, dbFile string) (MappingStore, RedemptionStore, error) {
+	switch storeKind {
+	case "csv":
+		redemptionStore, err := NewJournalRedemptionStore(journalFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open redemption journal: %w", err)
+		}
+		return CSVMappingStore{FilePath: mappingFile}, redemptionStore, nil
+	case "sqlite":
+		sqliteMappings, sqliteRedemptions, err := NewSQLiteStores(dbFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open sqlite database: %w", err)
+		}
+		if mappingFile != "" {
+			mappings, rowErrors, err := LoadMappingFromCSV(mappingFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to load mapping file: %w", err)
+			}
+			logRowErrors(rowErrors)
+			if err := sqliteMappings.Seed(mappings); err != nil {
+				return nil, nil, fmt.Errorf("unable to seed mappings: %w", err)
+			}
+		}
+		return sqliteMappings, sqliteRedemptions, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown store %q (expected csv or sqlite)", storeKind)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCSVMappingStoreLoad verifies that CSVMappingStore delegates to
+// LoadMappingFromCSV.
+func TestCSVMappingStoreLoad(t *testing.T) {
+	csvData := `staff_pass_id,team_name,created_at
+STAFF_H123804820G,BASS,1623772799000
+`
+	path := filepath.Join(t.TempDir(), "mapping.csv")
+	if err := os.WriteFile(path, []byte(csvData), 0644); err != nil {
+		t.Fatalf("unable to write mapping file: %v", err)
+	}
+
+	store := CSVMappingStore{FilePath: path}
+	mappings, err := store.Load()
+	if err != nil {
+		t.Fatalf("unable to load mappings: %v", err)
+	}
+	if len(mappings) != 1 || mappings[0].TeamName != "BASS" {
+		t.Errorf("unexpected mappings: %+v", mappings)
+	}
+}
+
+// TestNewRedemptionManagerWithStore verifies that a RedemptionManager built
+// from an arbitrary RedemptionStore replays prior state and persists new
+// redemptions through it.
+func TestNewRedemptionManagerWithStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redemptions.journal")
+
+	store, err := NewJournalRedemptionStore(path)
+	if err != nil {
+		t.Fatalf("unable to create journal store: %v", err)
+	}
+	rm, err := NewRedemptionManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("unable to create redemption manager: %v", err)
+	}
+	if _, err := rm.AddRedemption("BASS"); err != nil {
+		t.Fatalf("unable to add redemption: %v", err)
+	}
+	if err := rm.Close(); err != nil {
+		t.Fatalf("unable to close redemption manager: %v", err)
+	}
+
+	reopened, err := NewJournalRedemptionStore(path)
+	if err != nil {
+		t.Fatalf("unable to reopen journal store: %v", err)
+	}
+	restarted, err := NewRedemptionManagerWithStore(reopened)
+	if err != nil {
+		t.Fatalf("unable to recreate redemption manager: %v", err)
+	}
+	defer restarted.Close()
+
+	if restarted.IsEligible("BASS") {
+		t.Errorf("expected BASS to already be redeemed after restart")
+	}
+}